@@ -0,0 +1,292 @@
+// Package auth implements registration, login, and token-based
+// authentication for the API, backed by the users and tokens tables.
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// User is the authenticated principal injected into the request context
+// by Middleware.
+type User struct {
+	ID       int    `json:"id"`
+	Username string `json:"username"`
+	Role     string `json:"role"`
+}
+
+type contextKey string
+
+// UserContextKey is the context key under which the authenticated User
+// is stored by Middleware.
+const UserContextKey contextKey = "auth.user"
+
+// apiResponse mirrors the shape of the main package's APIResponse so
+// auth responses look identical to the rest of the API.
+type apiResponse struct {
+	Success bool        `json:"success"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
+	Error   string      `json:"error,omitempty"`
+}
+
+func writeJSON(w http.ResponseWriter, status int, resp apiResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// Service holds the dependencies needed to register, authenticate, and
+// authorize users.
+type Service struct {
+	DB        *sql.DB
+	JWTSecret []byte
+	TokenTTL  time.Duration
+	// Driver is the configured database/sql driver name ("sqlite3",
+	// "mysql", "postgres"), used to pick the right SQL placeholder
+	// syntax for this package's queries.
+	Driver string
+	// OnUserCreated, if set, is called after a user row is successfully
+	// inserted by RegisterHandler, so the caller can react to user-count
+	// changes (e.g. broadcasting a stats update) without this package
+	// importing back into main.
+	OnUserCreated func()
+}
+
+// NewService constructs an auth Service. tokenTTL defaults to 24h when
+// zero.
+func NewService(db *sql.DB, jwtSecret []byte, tokenTTL time.Duration, driver string) *Service {
+	if tokenTTL == 0 {
+		tokenTTL = 24 * time.Hour
+	}
+	return &Service{DB: db, JWTSecret: jwtSecret, TokenTTL: tokenTTL, Driver: driver}
+}
+
+// rebindQuery rewrites "?" placeholders in query to "$1", "$2", ... when
+// the service is configured for postgres, mirroring the main package's
+// driver-aware query helper.
+func (s *Service) rebindQuery(query string) string {
+	if s.Driver != "postgres" {
+		return query
+	}
+	n := 0
+	out := make([]byte, 0, len(query)+8)
+	for i := 0; i < len(query); i++ {
+		if query[i] == '?' {
+			n++
+			out = append(out, []byte(fmt.Sprintf("$%d", n))...)
+			continue
+		}
+		out = append(out, query[i])
+	}
+	return string(out)
+}
+
+type registerRequest struct {
+	Username string `json:"username"`
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+// RegisterHandler creates a new user row with a bcrypt-hashed password.
+func (s *Service) RegisterHandler(w http.ResponseWriter, r *http.Request) {
+	var req registerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, apiResponse{Success: false, Message: "Invalid JSON format", Error: err.Error()})
+		return
+	}
+
+	if req.Username == "" || req.Email == "" || req.Password == "" {
+		writeJSON(w, http.StatusBadRequest, apiResponse{Success: false, Message: "Username, email, and password are required"})
+		return
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, apiResponse{Success: false, Message: "Failed to hash password", Error: err.Error()})
+		return
+	}
+
+	res, err := s.DB.Exec(
+		s.rebindQuery(`INSERT INTO users (username, email, password_hash, role, is_active) VALUES (?, ?, ?, 'user', 1)`),
+		req.Username, req.Email, string(hash),
+	)
+	if err != nil {
+		writeJSON(w, http.StatusConflict, apiResponse{Success: false, Message: "Failed to create user", Error: err.Error()})
+		return
+	}
+
+	id, _ := res.LastInsertId()
+	if s.OnUserCreated != nil {
+		s.OnUserCreated()
+	}
+	writeJSON(w, http.StatusCreated, apiResponse{
+		Success: true,
+		Message: "User registered",
+		Data:    User{ID: int(id), Username: req.Username, Role: "user"},
+	})
+}
+
+type loginRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// LoginHandler verifies credentials, signs a short-lived JWT, and
+// persists it in the tokens table so LogoutHandler can revoke it.
+func (s *Service) LoginHandler(w http.ResponseWriter, r *http.Request) {
+	var req loginRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, apiResponse{Success: false, Message: "Invalid JSON format", Error: err.Error()})
+		return
+	}
+
+	var user User
+	var passwordHash string
+	err := s.DB.QueryRow(
+		s.rebindQuery(`SELECT id, username, role, password_hash FROM users WHERE username = ? AND is_active = 1`),
+		req.Username,
+	).Scan(&user.ID, &user.Username, &user.Role, &passwordHash)
+	if err != nil {
+		writeJSON(w, http.StatusUnauthorized, apiResponse{Success: false, Message: "Invalid username or password"})
+		return
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(passwordHash), []byte(req.Password)); err != nil {
+		writeJSON(w, http.StatusUnauthorized, apiResponse{Success: false, Message: "Invalid username or password"})
+		return
+	}
+
+	expiresAt := time.Now().Add(s.TokenTTL)
+	claims := jwt.MapClaims{
+		"sub":  user.ID,
+		"name": user.Username,
+		"role": user.Role,
+		"exp":  expiresAt.Unix(),
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	signed, err := token.SignedString(s.JWTSecret)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, apiResponse{Success: false, Message: "Failed to sign token", Error: err.Error()})
+		return
+	}
+
+	if _, err := s.DB.Exec(
+		s.rebindQuery(`INSERT INTO tokens (user_id, token, expires_at) VALUES (?, ?, ?)`),
+		user.ID, signed, expiresAt,
+	); err != nil {
+		writeJSON(w, http.StatusInternalServerError, apiResponse{Success: false, Message: "Failed to persist token", Error: err.Error()})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, apiResponse{
+		Success: true,
+		Message: "Login successful",
+		Data:    map[string]interface{}{"token": signed, "user": user, "expires_at": expiresAt},
+	})
+}
+
+// LogoutHandler revokes the bearer token presented in the request.
+func (s *Service) LogoutHandler(w http.ResponseWriter, r *http.Request) {
+	token := bearerToken(r)
+	if token == "" {
+		writeJSON(w, http.StatusBadRequest, apiResponse{Success: false, Message: "Missing bearer token"})
+		return
+	}
+
+	if _, err := s.DB.Exec(s.rebindQuery(`DELETE FROM tokens WHERE token = ?`), token); err != nil {
+		writeJSON(w, http.StatusInternalServerError, apiResponse{Success: false, Message: "Failed to revoke token", Error: err.Error()})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, apiResponse{Success: true, Message: "Logged out"})
+}
+
+func bearerToken(r *http.Request) string {
+	header := r.Header.Get("Authorization")
+	if !strings.HasPrefix(header, "Bearer ") {
+		return ""
+	}
+	return strings.TrimPrefix(header, "Bearer ")
+}
+
+var errTokenRevoked = errors.New("token has been revoked")
+
+// Middleware validates the Authorization: Bearer <token> header against
+// both the JWT signature/expiry and the tokens table (so LogoutHandler
+// can revoke a token before its natural expiry), then injects the
+// authenticated User into the request context.
+func (s *Service) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw := bearerToken(r)
+		if raw == "" {
+			writeJSON(w, http.StatusUnauthorized, apiResponse{Success: false, Message: "Missing bearer token"})
+			return
+		}
+
+		token, err := jwt.Parse(raw, func(t *jwt.Token) (interface{}, error) {
+			return s.JWTSecret, nil
+		})
+		if err != nil || !token.Valid {
+			writeJSON(w, http.StatusUnauthorized, apiResponse{Success: false, Message: "Invalid or expired token"})
+			return
+		}
+
+		var exists bool
+		if err := s.DB.QueryRow(s.rebindQuery(`SELECT EXISTS(SELECT 1 FROM tokens WHERE token = ? AND expires_at > ?)`), raw, time.Now()).Scan(&exists); err != nil || !exists {
+			writeJSON(w, http.StatusUnauthorized, apiResponse{Success: false, Message: errTokenRevoked.Error()})
+			return
+		}
+
+		claims := token.Claims.(jwt.MapClaims)
+		var user User
+		if name, ok := claims["name"].(string); ok {
+			user.Username = name
+		}
+		if role, ok := claims["role"].(string); ok {
+			user.Role = role
+		}
+		if sub, ok := claims["sub"].(float64); ok {
+			user.ID = int(sub)
+		}
+
+		ctx := context.WithValue(r.Context(), UserContextKey, user)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// RequireRole returns middleware that rejects requests whose
+// authenticated user (injected by Middleware) does not hold one of the
+// given roles.
+func RequireRole(roles ...string) func(http.Handler) http.Handler {
+	allowed := make(map[string]bool, len(roles))
+	for _, role := range roles {
+		allowed[role] = true
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user, ok := r.Context().Value(UserContextKey).(User)
+			if !ok || !allowed[user.Role] {
+				writeJSON(w, http.StatusForbidden, apiResponse{Success: false, Message: "Insufficient permissions"})
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// FromContext retrieves the authenticated User injected by Middleware.
+func FromContext(ctx context.Context) (User, bool) {
+	user, ok := ctx.Value(UserContextKey).(User)
+	return user, ok
+}
@@ -0,0 +1,196 @@
+package auth
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// newTestService builds a Service backed by an in-memory sqlite3 database
+// with just enough schema for the auth handlers under test.
+func newTestService(t *testing.T) *Service {
+	t.Helper()
+
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	schema := `
+		CREATE TABLE users (
+			id            INTEGER PRIMARY KEY AUTOINCREMENT,
+			username      TEXT NOT NULL UNIQUE,
+			email         TEXT NOT NULL UNIQUE,
+			password_hash TEXT NOT NULL DEFAULT '',
+			role          TEXT NOT NULL DEFAULT 'user',
+			is_active     INTEGER NOT NULL DEFAULT 1
+		);
+		CREATE TABLE tokens (
+			id         INTEGER PRIMARY KEY AUTOINCREMENT,
+			user_id    INTEGER NOT NULL,
+			token      TEXT NOT NULL UNIQUE,
+			expires_at TIMESTAMP NOT NULL
+		);
+	`
+	if _, err := db.Exec(schema); err != nil {
+		t.Fatalf("failed to create test schema: %v", err)
+	}
+
+	return NewService(db, []byte("test-secret"), time.Hour, "sqlite3")
+}
+
+func doRegister(t *testing.T, s *Service, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/api/register", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	s.RegisterHandler(rec, req)
+	return rec
+}
+
+func TestRegisterHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+	}{
+		{"valid registration", `{"username":"alice","email":"alice@example.com","password":"hunter2"}`, http.StatusCreated},
+		{"missing password", `{"username":"bob","email":"bob@example.com"}`, http.StatusBadRequest},
+		{"invalid json", `{not json`, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestService(t)
+			rec := doRegister(t, s, tt.body)
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d (body: %s)", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestRegisterHandlerCallsOnUserCreated(t *testing.T) {
+	s := newTestService(t)
+	called := false
+	s.OnUserCreated = func() { called = true }
+
+	rec := doRegister(t, s, `{"username":"alice","email":"alice@example.com","password":"hunter2"}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want %d (body: %s)", rec.Code, http.StatusCreated, rec.Body.String())
+	}
+	if !called {
+		t.Error("OnUserCreated was not called after a successful registration")
+	}
+}
+
+func TestRegisterHandlerDuplicateUsername(t *testing.T) {
+	s := newTestService(t)
+	body := `{"username":"alice","email":"alice@example.com","password":"hunter2"}`
+
+	if rec := doRegister(t, s, body); rec.Code != http.StatusCreated {
+		t.Fatalf("first registration status = %d, want %d", rec.Code, http.StatusCreated)
+	}
+	if rec := doRegister(t, s, body); rec.Code != http.StatusConflict {
+		t.Errorf("duplicate registration status = %d, want %d", rec.Code, http.StatusConflict)
+	}
+}
+
+func TestLoginHandler(t *testing.T) {
+	s := newTestService(t)
+	doRegister(t, s, `{"username":"alice","email":"alice@example.com","password":"hunter2"}`)
+
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+	}{
+		{"correct credentials", `{"username":"alice","password":"hunter2"}`, http.StatusOK},
+		{"wrong password", `{"username":"alice","password":"wrong"}`, http.StatusUnauthorized},
+		{"unknown user", `{"username":"nobody","password":"hunter2"}`, http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/login", strings.NewReader(tt.body))
+			rec := httptest.NewRecorder()
+			s.LoginHandler(rec, req)
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d (body: %s)", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestMiddlewareAndLogout(t *testing.T) {
+	s := newTestService(t)
+	doRegister(t, s, `{"username":"alice","email":"alice@example.com","password":"hunter2"}`)
+
+	loginReq := httptest.NewRequest(http.MethodPost, "/api/login", strings.NewReader(`{"username":"alice","password":"hunter2"}`))
+	loginRec := httptest.NewRecorder()
+	s.LoginHandler(loginRec, loginReq)
+	if loginRec.Code != http.StatusOK {
+		t.Fatalf("login status = %d, want %d (body: %s)", loginRec.Code, http.StatusOK, loginRec.Body.String())
+	}
+
+	var loginResp struct {
+		Data struct {
+			Token string `json:"token"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(loginRec.Body.Bytes(), &loginResp); err != nil {
+		t.Fatalf("failed to decode login response: %v", err)
+	}
+	token := loginResp.Data.Token
+	if token == "" {
+		t.Fatal("login response had no token")
+	}
+
+	protected := s.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, ok := FromContext(r.Context())
+		if !ok {
+			t.Error("authenticated user missing from context")
+		}
+		if user.Username != "alice" {
+			t.Errorf("user.Username = %q, want %q", user.Username, "alice")
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	authedReq := httptest.NewRequest(http.MethodGet, "/api/stats", nil)
+	authedReq.Header.Set("Authorization", "Bearer "+token)
+	authedRec := httptest.NewRecorder()
+	protected.ServeHTTP(authedRec, authedReq)
+	if authedRec.Code != http.StatusOK {
+		t.Errorf("authenticated request status = %d, want %d", authedRec.Code, http.StatusOK)
+	}
+
+	unauthedReq := httptest.NewRequest(http.MethodGet, "/api/stats", nil)
+	unauthedRec := httptest.NewRecorder()
+	protected.ServeHTTP(unauthedRec, unauthedReq)
+	if unauthedRec.Code != http.StatusUnauthorized {
+		t.Errorf("unauthenticated request status = %d, want %d", unauthedRec.Code, http.StatusUnauthorized)
+	}
+
+	logoutReq := httptest.NewRequest(http.MethodPost, "/api/logout", nil)
+	logoutReq.Header.Set("Authorization", "Bearer "+token)
+	logoutRec := httptest.NewRecorder()
+	s.LogoutHandler(logoutRec, logoutReq)
+	if logoutRec.Code != http.StatusOK {
+		t.Fatalf("logout status = %d, want %d (body: %s)", logoutRec.Code, http.StatusOK, logoutRec.Body.String())
+	}
+
+	revokedReq := httptest.NewRequest(http.MethodGet, "/api/stats", nil)
+	revokedReq.Header.Set("Authorization", "Bearer "+token)
+	revokedRec := httptest.NewRecorder()
+	protected.ServeHTTP(revokedRec, revokedReq)
+	if revokedRec.Code != http.StatusUnauthorized {
+		t.Errorf("request with revoked token status = %d, want %d", revokedRec.Code, http.StatusUnauthorized)
+	}
+}
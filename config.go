@@ -0,0 +1,195 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"syscall"
+
+	"gopkg.in/yaml.v3"
+)
+
+// defaultJWTSecret is the placeholder JWTSecret ships with; validateConfig
+// refuses to start with it outside development.
+const defaultJWTSecret = "dev-secret-change-me"
+
+// currentConfig holds the live, hot-reloadable Config. Handlers and
+// middleware must read it through getConfig() rather than holding onto a
+// stale pointer, so a SIGHUP-triggered reload takes effect immediately.
+var currentConfig atomic.Pointer[Config]
+
+// getConfig returns the currently active configuration.
+func getConfig() *Config {
+	return currentConfig.Load()
+}
+
+// buildConfig layers the configured defaults, an optional JSON/YAML file
+// at path, and environment variables (highest precedence), then
+// validates the result.
+func buildConfig(path string) (*Config, error) {
+	cfg := &Config{
+		Port:                   8080,
+		DatabaseDSN:            "./data/sample.db",
+		DatabaseDriver:         "sqlite3",
+		Environment:            "development",
+		Debug:                  true,
+		JWTSecret:              defaultJWTSecret,
+		ShutdownTimeoutSeconds: 15,
+		LogLevel:               "info",
+		CORSAllowOrigins:       []string{"*"},
+	}
+
+	if path != "" {
+		if err := overlayConfigFile(cfg, path); err != nil {
+			return nil, fmt.Errorf("failed to load config file %s: %v", path, err)
+		}
+	}
+
+	overlayConfigEnv(cfg)
+
+	if err := validateConfig(cfg); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// overlayConfigFile reads a JSON or YAML file (by extension) into cfg.
+func overlayConfigFile(cfg *Config, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	switch filepath.Ext(path) {
+	case ".yaml", ".yml":
+		return yaml.Unmarshal(data, cfg)
+	default:
+		return json.Unmarshal(data, cfg)
+	}
+}
+
+// overlayConfigEnv applies environment variable overrides. APP_PORT and
+// GO_ENV keep the meaning they had in the earlier getenv-based loader;
+// the database location is read from DATABASE_DSN, and the older
+// DATABASE_PATH variable is not read.
+func overlayConfigEnv(cfg *Config) {
+	if port := os.Getenv("APP_PORT"); port != "" {
+		if p, err := strconv.Atoi(port); err == nil {
+			cfg.Port = p
+		}
+	}
+
+	if dsn := os.Getenv("DATABASE_DSN"); dsn != "" {
+		cfg.DatabaseDSN = dsn
+	}
+
+	if driver := os.Getenv("DATABASE_DRIVER"); driver != "" {
+		cfg.DatabaseDriver = driver
+	}
+
+	if secret := os.Getenv("JWT_SECRET"); secret != "" {
+		cfg.JWTSecret = secret
+	}
+
+	if timeout := os.Getenv("SHUTDOWN_TIMEOUT_SECONDS"); timeout != "" {
+		if t, err := strconv.Atoi(timeout); err == nil {
+			cfg.ShutdownTimeoutSeconds = t
+		}
+	}
+
+	if user := os.Getenv("APP_USER"); user != "" {
+		cfg.User = user
+	}
+	if group := os.Getenv("APP_GROUP"); group != "" {
+		cfg.Group = group
+	}
+	if cert := os.Getenv("TLS_CERT_FILE"); cert != "" {
+		cfg.CertFile = cert
+	}
+	if key := os.Getenv("TLS_KEY_FILE"); key != "" {
+		cfg.KeyFile = key
+	}
+
+	if env := os.Getenv("GO_ENV"); env != "" {
+		cfg.Environment = env
+		cfg.Debug = env != "production"
+	}
+
+	if level := os.Getenv("LOG_LEVEL"); level != "" {
+		cfg.LogLevel = level
+	}
+
+	if origins := os.Getenv("CORS_ALLOW_ORIGINS"); origins != "" {
+		cfg.CORSAllowOrigins = strings.Split(origins, ",")
+	}
+}
+
+// validateConfig rejects configurations that would leave the server in
+// an unusable or insecure state.
+func validateConfig(cfg *Config) error {
+	if cfg.Port <= 0 || cfg.Port > 65535 {
+		return fmt.Errorf("invalid port: %d", cfg.Port)
+	}
+	if cfg.DatabaseDriver == "" {
+		return fmt.Errorf("database driver must not be empty")
+	}
+	if cfg.JWTSecret == "" {
+		return fmt.Errorf("JWT secret must not be empty")
+	}
+	if cfg.Environment != "development" && cfg.JWTSecret == defaultJWTSecret {
+		return fmt.Errorf("JWT secret must be set to a non-default value outside development")
+	}
+	if len(cfg.CORSAllowOrigins) == 0 {
+		return fmt.Errorf("CORS allow-list must not be empty")
+	}
+	return nil
+}
+
+// watchConfigReload re-parses the config file on SIGHUP and atomically
+// swaps currentConfig, so log level, debug flag, and CORS allow-list can
+// change without restarting the process. Settings that require rebinding
+// the listener or reopening the database (port, TLS, DSN) are loaded
+// once at startup and are not affected by a reload.
+func watchConfigReload(path string) {
+	if path == "" {
+		return
+	}
+
+	hupCh := make(chan os.Signal, 1)
+	signal.Notify(hupCh, syscall.SIGHUP)
+
+	go func() {
+		for range hupCh {
+			cfg, err := buildConfig(path)
+			if err != nil {
+				log.Printf("Config reload from %s failed, keeping previous config: %v", path, err)
+				continue
+			}
+
+			currentConfig.Store(cfg)
+			initLogger(cfg.Debug)
+			log.Printf("Configuration reloaded from %s", path)
+		}
+	}()
+}
+
+// corsAllowOrigin picks the Access-Control-Allow-Origin value for a
+// request's Origin header against the configured allow-list.
+func corsAllowOrigin(cfg *Config, requestOrigin string) string {
+	for _, allowed := range cfg.CORSAllowOrigins {
+		if allowed == "*" {
+			return "*"
+		}
+		if allowed == requestOrigin {
+			return requestOrigin
+		}
+	}
+	return ""
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"embed"
+	"fmt"
+	"strings"
+
+	"github.com/golang-migrate/migrate/v4"
+	_ "github.com/golang-migrate/migrate/v4/database/mysql"
+	_ "github.com/golang-migrate/migrate/v4/database/postgres"
+	_ "github.com/golang-migrate/migrate/v4/database/sqlite3"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+)
+
+//go:embed migrations
+var migrationFiles embed.FS
+
+// runMigrations applies every pending migration under the embedded
+// migrations/<driver> directory for the configured database, creating
+// the users, projects, and tasks tables on first run instead of
+// assuming they already exist. Each driver gets its own migration set
+// because DDL (auto-increment syntax, column types) isn't portable
+// across sqlite3/mysql/postgres.
+func runMigrations(driver, dsn string) error {
+	source, err := iofs.New(migrationFiles, "migrations/"+migrationDir(driver))
+	if err != nil {
+		return fmt.Errorf("failed to load embedded migrations: %v", err)
+	}
+
+	m, err := migrate.NewWithSourceInstance("iofs", source, migrationDBURL(driver, dsn))
+	if err != nil {
+		return fmt.Errorf("failed to init migration runner: %v", err)
+	}
+
+	if err := m.Up(); err != nil && err != migrate.ErrNoChange {
+		return err
+	}
+	return nil
+}
+
+// migrationDir maps a configured driver to its migrations subdirectory.
+func migrationDir(driver string) string {
+	switch driver {
+	case "mysql":
+		return "mysql"
+	case "postgres":
+		return "postgres"
+	default:
+		return "sqlite3"
+	}
+}
+
+// migrationDBURL adapts our driver/DSN pair to the URL scheme golang-migrate
+// expects for each supported backend.
+func migrationDBURL(driver, dsn string) string {
+	switch driver {
+	case "mysql":
+		return "mysql://" + dsn
+	case "postgres":
+		return "postgres://" + dsn
+	default:
+		return "sqlite3://" + dsn
+	}
+}
+
+// placeholder returns the parameter marker for position n (1-indexed) in
+// the configured driver's SQL dialect: sqlite3/mysql use "?", postgres
+// uses "$n".
+func placeholder(driver string, n int) string {
+	if driver == "postgres" {
+		return fmt.Sprintf("$%d", n)
+	}
+	return "?"
+}
+
+// rebindQuery rewrites the sqlite3/mysql-style "?" placeholders in query
+// to "$1", "$2", ... when the configured driver is postgres, so call
+// sites can write one portable query string instead of branching on
+// driver themselves.
+func rebindQuery(query string) string {
+	if getConfig().DatabaseDriver != "postgres" {
+		return query
+	}
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			fmt.Fprintf(&b, "$%d", n)
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
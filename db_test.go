@@ -0,0 +1,70 @@
+package main
+
+import "testing"
+
+func TestPlaceholder(t *testing.T) {
+	tests := []struct {
+		name   string
+		driver string
+		n      int
+		want   string
+	}{
+		{"sqlite3 first", "sqlite3", 1, "?"},
+		{"mysql second", "mysql", 2, "?"},
+		{"postgres first", "postgres", 1, "$1"},
+		{"postgres third", "postgres", 3, "$3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := placeholder(tt.driver, tt.n); got != tt.want {
+				t.Errorf("placeholder(%q, %d) = %q, want %q", tt.driver, tt.n, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRebindQuery(t *testing.T) {
+	tests := []struct {
+		name   string
+		driver string
+		query  string
+		want   string
+	}{
+		{
+			name:   "sqlite3 leaves placeholders alone",
+			driver: "sqlite3",
+			query:  "SELECT * FROM users WHERE id = ? AND role = ?",
+			want:   "SELECT * FROM users WHERE id = ? AND role = ?",
+		},
+		{
+			name:   "mysql leaves placeholders alone",
+			driver: "mysql",
+			query:  "SELECT * FROM users WHERE id = ? AND role = ?",
+			want:   "SELECT * FROM users WHERE id = ? AND role = ?",
+		},
+		{
+			name:   "postgres rewrites placeholders in order",
+			driver: "postgres",
+			query:  "SELECT * FROM users WHERE id = ? AND role = ?",
+			want:   "SELECT * FROM users WHERE id = $1 AND role = $2",
+		},
+		{
+			name:   "postgres with no placeholders",
+			driver: "postgres",
+			query:  "SELECT COUNT(*) FROM users",
+			want:   "SELECT COUNT(*) FROM users",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{DatabaseDriver: tt.driver}
+			currentConfig.Store(cfg)
+
+			if got := rebindQuery(tt.query); got != tt.want {
+				t.Errorf("rebindQuery(%q) = %q, want %q", tt.query, got, tt.want)
+			}
+		})
+	}
+}
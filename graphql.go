@@ -0,0 +1,374 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+
+	"github.com/graphql-go/graphql"
+	"github.com/gorilla/websocket"
+)
+
+// graphqlRequest is the standard GraphQL-over-HTTP request envelope.
+type graphqlRequest struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// statsSubscriber holds the set of open websocket connections listening
+// for stats deltas, guarded by statsMu.
+var (
+	statsMu         sync.Mutex
+	statsSubscriber = map[*websocket.Conn]bool{}
+	statsUpgrader   = websocket.Upgrader{
+		ReadBufferSize:  1024,
+		WriteBufferSize: 1024,
+		CheckOrigin:     func(r *http.Request) bool { return true },
+	}
+)
+
+// taskType, projectType, and userType describe the GraphQL shape of the
+// existing REST resources, including the nested project<->task<->user
+// relationships the REST API can't express in one round-trip.
+var (
+	taskType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Task",
+		Fields: graphql.Fields{
+			"id":        &graphql.Field{Type: graphql.Int},
+			"projectId": &graphql.Field{Type: graphql.Int},
+			"title":     &graphql.Field{Type: graphql.String},
+			"status":    &graphql.Field{Type: graphql.String},
+		},
+	})
+
+	userType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "User",
+		Fields: graphql.Fields{
+			"id":        &graphql.Field{Type: graphql.Int},
+			"username":  &graphql.Field{Type: graphql.String},
+			"email":     &graphql.Field{Type: graphql.String},
+			"firstName": &graphql.Field{Type: graphql.String},
+			"lastName":  &graphql.Field{Type: graphql.String},
+			"isActive":  &graphql.Field{Type: graphql.Boolean},
+		},
+	})
+
+	projectType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Project",
+		Fields: graphql.Fields{
+			"id":          &graphql.Field{Type: graphql.Int},
+			"name":        &graphql.Field{Type: graphql.String},
+			"description": &graphql.Field{Type: graphql.String},
+			"status":      &graphql.Field{Type: graphql.String},
+			"priority":    &graphql.Field{Type: graphql.String},
+			"budget":      &graphql.Field{Type: graphql.Float},
+			"tasks": &graphql.Field{
+				Type:    graphql.NewList(taskType),
+				Resolve: resolveProjectTasks,
+			},
+			"owner": &graphql.Field{
+				Type:    userType,
+				Resolve: resolveProjectOwner,
+			},
+		},
+	})
+
+	statsType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Stats",
+		Fields: graphql.Fields{
+			"activeUsers": &graphql.Field{Type: graphql.Int},
+			"totalTasks":  &graphql.Field{Type: graphql.Int},
+		},
+	})
+)
+
+// resolveProjectTasks loads the tasks belonging to a project, letting a
+// single GraphQL query fetch a project and its tasks in one round-trip.
+func resolveProjectTasks(p graphql.ResolveParams) (interface{}, error) {
+	project, ok := p.Source.(Project)
+	if !ok || db == nil {
+		return nil, nil
+	}
+
+	rows, err := db.Query(rebindQuery(`SELECT id, project_id, title, status FROM tasks WHERE project_id = ?`), project.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tasks []map[string]interface{}
+	for rows.Next() {
+		var id, projectID int
+		var title, status string
+		if err := rows.Scan(&id, &projectID, &title, &status); err != nil {
+			continue
+		}
+		tasks = append(tasks, map[string]interface{}{
+			"id":        id,
+			"projectId": projectID,
+			"title":     title,
+			"status":    status,
+		})
+	}
+	return tasks, nil
+}
+
+// resolveProjectOwner loads the user that owns a project.
+func resolveProjectOwner(p graphql.ResolveParams) (interface{}, error) {
+	project, ok := p.Source.(Project)
+	if !ok || db == nil {
+		return nil, nil
+	}
+
+	var user User
+	err := db.QueryRow(rebindQuery(`
+		SELECT u.id, u.username, u.email, u.first_name, u.last_name, u.is_active
+		FROM users u
+		JOIN projects p ON p.owner_id = u.id
+		WHERE p.id = ?
+	`), project.ID).Scan(&user.ID, &user.Username, &user.Email, &user.FirstName, &user.LastName, &user.IsActive)
+	if err != nil {
+		return nil, nil
+	}
+	return user, nil
+}
+
+// paginationArgs are the offset/limit arguments shared across list fields.
+var paginationArgs = graphql.FieldConfigArgument{
+	"limit":  &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: 10},
+	"offset": &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: 0},
+}
+
+// rootQuery exposes the same User, Project, and stats resources as the
+// REST handlers, but with nested fields and pagination arguments.
+var rootQuery = graphql.NewObject(graphql.ObjectConfig{
+	Name: "Query",
+	Fields: graphql.Fields{
+		"users": &graphql.Field{
+			Type:    graphql.NewList(userType),
+			Args:    paginationArgs,
+			Resolve: resolveUsersQuery,
+		},
+		"projects": &graphql.Field{
+			Type:    graphql.NewList(projectType),
+			Args:    paginationArgs,
+			Resolve: resolveProjectsQuery,
+		},
+		"stats": &graphql.Field{
+			Type:    statsType,
+			Resolve: resolveStatsQuery,
+		},
+	},
+})
+
+func resolveUsersQuery(p graphql.ResolveParams) (interface{}, error) {
+	if db == nil {
+		return nil, nil
+	}
+	limit, _ := p.Args["limit"].(int)
+	offset, _ := p.Args["offset"].(int)
+
+	driver := getConfig().DatabaseDriver
+	query := fmt.Sprintf(`
+		SELECT id, username, email, first_name, last_name, is_active, created_at
+		FROM users
+		WHERE is_active = 1
+		ORDER BY created_at DESC
+		LIMIT %s OFFSET %s
+	`, placeholder(driver, 1), placeholder(driver, 2))
+
+	rows, err := db.Query(query, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []User
+	for rows.Next() {
+		var user User
+		if err := rows.Scan(&user.ID, &user.Username, &user.Email, &user.FirstName, &user.LastName, &user.IsActive, &user.CreatedAt); err == nil {
+			users = append(users, user)
+		}
+	}
+	return users, nil
+}
+
+func resolveProjectsQuery(p graphql.ResolveParams) (interface{}, error) {
+	if db == nil {
+		return nil, nil
+	}
+	limit, _ := p.Args["limit"].(int)
+	offset, _ := p.Args["offset"].(int)
+
+	driver := getConfig().DatabaseDriver
+	query := fmt.Sprintf(`
+		SELECT id, name, description, status, priority, budget, start_date, end_date
+		FROM projects
+		ORDER BY created_at DESC
+		LIMIT %s OFFSET %s
+	`, placeholder(driver, 1), placeholder(driver, 2))
+
+	rows, err := db.Query(query, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var projects []Project
+	for rows.Next() {
+		var project Project
+		if err := rows.Scan(&project.ID, &project.Name, &project.Description, &project.Status, &project.Priority, &project.Budget, &project.StartDate, &project.EndDate); err == nil {
+			projects = append(projects, project)
+		}
+	}
+	return projects, nil
+}
+
+func resolveStatsQuery(p graphql.ResolveParams) (interface{}, error) {
+	if db == nil {
+		return nil, nil
+	}
+	var activeUsers, totalTasks int
+	db.QueryRow("SELECT COUNT(*) FROM users WHERE is_active = 1").Scan(&activeUsers)
+	db.QueryRow("SELECT COUNT(*) FROM tasks").Scan(&totalTasks)
+	return map[string]interface{}{
+		"activeUsers": activeUsers,
+		"totalTasks":  totalTasks,
+	}, nil
+}
+
+// rootSubscription declares the statsDelta field in the schema. The
+// stream itself is served by statsSubscriptionHandler on a /query
+// websocket upgrade, which pushes one Stats payload per
+// broadcastStatsDelta call without evaluating a subscription document.
+var rootSubscription = graphql.NewObject(graphql.ObjectConfig{
+	Name: "Subscription",
+	Fields: graphql.Fields{
+		"statsDelta": &graphql.Field{
+			Type:    statsType,
+			Resolve: resolveStatsQuery,
+		},
+	},
+})
+
+var graphqlSchema graphql.Schema
+
+func init() {
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query:        rootQuery,
+		Subscription: rootSubscription,
+	})
+	if err != nil {
+		log.Fatalf("Failed to build GraphQL schema: %v", err)
+	}
+	graphqlSchema = schema
+}
+
+// graphqlHandler serves schema-first GraphQL queries over HTTP POST,
+// backed by the same *sql.DB used by the REST handlers; the schema
+// defines no mutations. A GET websocket upgrade is handed to
+// statsSubscriptionHandler.
+func graphqlHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodGet && r.Header.Get("Upgrade") == "websocket" {
+		statsSubscriptionHandler(w, r)
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		jsonResponse(w, http.StatusMethodNotAllowed, APIResponse{
+			Success: false,
+			Message: "Only POST is allowed for /query",
+		})
+		return
+	}
+
+	var req graphqlRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		jsonResponse(w, http.StatusBadRequest, APIResponse{
+			Success: false,
+			Message: "Invalid GraphQL request body",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	result := graphql.Do(graphql.Params{
+		Schema:         graphqlSchema,
+		RequestString:  req.Query,
+		VariableValues: req.Variables,
+		OperationName:  req.OperationName,
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// statsSubscriptionHandler upgrades to a websocket connection and streams
+// statsDelta payloads whenever broadcastStatsDelta is called.
+func statsSubscriptionHandler(w http.ResponseWriter, r *http.Request) {
+	conn, err := statsUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Printf("Failed to upgrade stats subscription: %v", err)
+		return
+	}
+
+	statsMu.Lock()
+	statsSubscriber[conn] = true
+	statsMu.Unlock()
+
+	defer func() {
+		statsMu.Lock()
+		delete(statsSubscriber, conn)
+		statsMu.Unlock()
+		conn.Close()
+	}()
+
+	// Drain and discard client frames until the connection closes.
+	for {
+		if _, _, err := conn.ReadMessage(); err != nil {
+			return
+		}
+	}
+}
+
+// broadcastStatsDelta pushes the current stats snapshot to every
+// subscribed websocket client; call after any mutation that changes
+// user/task counts.
+func broadcastStatsDelta() {
+	if db == nil {
+		return
+	}
+	var activeUsers, totalTasks int
+	db.QueryRow("SELECT COUNT(*) FROM users WHERE is_active = 1").Scan(&activeUsers)
+	db.QueryRow("SELECT COUNT(*) FROM tasks").Scan(&totalTasks)
+
+	delta := map[string]interface{}{
+		"activeUsers": activeUsers,
+		"totalTasks":  totalTasks,
+	}
+
+	statsMu.Lock()
+	defer statsMu.Unlock()
+	for conn := range statsSubscriber {
+		if err := conn.WriteJSON(delta); err != nil {
+			conn.Close()
+			delete(statsSubscriber, conn)
+		}
+	}
+}
+
+// playgroundHandler serves a minimal GraphQL Playground UI pointed at /query.
+func playgroundHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	page := `<!DOCTYPE html>
+<html>
+<head><title>GraphQL Playground</title></head>
+<body style="margin:0;">
+	<iframe src="https://embed.apollo-server.com/?endpoint=/query" style="border:none;width:100%;height:100vh;"></iframe>
+</body>
+</html>`
+	w.Write([]byte(page))
+}
@@ -0,0 +1,61 @@
+//go:build !windows
+
+package main
+
+import (
+	"fmt"
+	"os/user"
+	"strconv"
+	"syscall"
+)
+
+// dropPrivileges switches the running process to the given user/group
+// after the listener has already bound its (possibly privileged) port,
+// the same pattern cc-backend uses to allow binding :443 without running
+// the whole process as root.
+func dropPrivileges(username, groupname string) error {
+	// Clear supplementary groups inherited from root before setgid/setuid,
+	// otherwise they're retained and the drop doesn't actually confine the
+	// process to the target user/group.
+	if err := syscall.Setgroups(nil); err != nil {
+		return fmt.Errorf("failed to clear supplementary groups: %v", err)
+	}
+
+	if groupname != "" {
+		gid, err := lookupGroupID(groupname)
+		if err != nil {
+			return fmt.Errorf("failed to resolve group %q: %v", groupname, err)
+		}
+		if err := syscall.Setgid(gid); err != nil {
+			return fmt.Errorf("failed to setgid(%d): %v", gid, err)
+		}
+	}
+
+	if username != "" {
+		uid, err := lookupUserID(username)
+		if err != nil {
+			return fmt.Errorf("failed to resolve user %q: %v", username, err)
+		}
+		if err := syscall.Setuid(uid); err != nil {
+			return fmt.Errorf("failed to setuid(%d): %v", uid, err)
+		}
+	}
+
+	return nil
+}
+
+func lookupUserID(username string) (int, error) {
+	u, err := user.Lookup(username)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(u.Uid)
+}
+
+func lookupGroupID(groupname string) (int, error) {
+	g, err := user.LookupGroup(groupname)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(g.Gid)
+}
@@ -0,0 +1,334 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// Task mirrors a row in the tasks table referenced by statsHandler.
+type Task struct {
+	ID        int    `json:"id"`
+	ProjectID int    `json:"project_id"`
+	Title     string `json:"title"`
+	Status    string `json:"status"`
+}
+
+// idFromRoute parses the {id} path variable, writing a 400 response and
+// returning ok=false if it isn't a valid integer.
+func idFromRoute(w http.ResponseWriter, r *http.Request) (int, bool) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		jsonResponse(w, http.StatusBadRequest, APIResponse{
+			Success: false,
+			Message: "Invalid id",
+			Error:   err.Error(),
+		})
+		return 0, false
+	}
+	return id, true
+}
+
+// getUserByIDHandler returns a single user, or 404 if it doesn't exist.
+func getUserByIDHandler(w http.ResponseWriter, r *http.Request) {
+	id, ok := idFromRoute(w, r)
+	if !ok {
+		return
+	}
+	if db == nil {
+		jsonResponse(w, http.StatusInternalServerError, APIResponse{Success: false, Message: "Database not available"})
+		return
+	}
+
+	var user User
+	err := db.QueryRow(
+		rebindQuery(`SELECT id, username, email, first_name, last_name, is_active, created_at FROM users WHERE id = ?`), id,
+	).Scan(&user.ID, &user.Username, &user.Email, &user.FirstName, &user.LastName, &user.IsActive, &user.CreatedAt)
+	if err != nil {
+		jsonResponse(w, http.StatusNotFound, APIResponse{Success: false, Message: fmt.Sprintf("User %d not found", id)})
+		return
+	}
+
+	jsonResponse(w, http.StatusOK, APIResponse{Success: true, Message: "User retrieved", Data: user})
+}
+
+// updateUserHandler updates the mutable fields of a user.
+func updateUserHandler(w http.ResponseWriter, r *http.Request) {
+	id, ok := idFromRoute(w, r)
+	if !ok {
+		return
+	}
+	if db == nil {
+		jsonResponse(w, http.StatusInternalServerError, APIResponse{Success: false, Message: "Database not available"})
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		jsonResponse(w, http.StatusBadRequest, APIResponse{Success: false, Message: "Failed to read request body", Error: err.Error()})
+		return
+	}
+
+	var user User
+	if err := json.Unmarshal(body, &user); err != nil {
+		jsonResponse(w, http.StatusBadRequest, APIResponse{Success: false, Message: "Invalid JSON format", Error: err.Error()})
+		return
+	}
+
+	res, err := db.Exec(
+		rebindQuery(`UPDATE users SET email = ?, first_name = ?, last_name = ?, is_active = ? WHERE id = ?`),
+		user.Email, user.FirstName, user.LastName, user.IsActive, id,
+	)
+	if err != nil {
+		jsonResponse(w, http.StatusInternalServerError, APIResponse{Success: false, Message: "Failed to update user", Error: err.Error()})
+		return
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		jsonResponse(w, http.StatusNotFound, APIResponse{Success: false, Message: fmt.Sprintf("User %d not found", id)})
+		return
+	}
+
+	user.ID = id
+	broadcastStatsDelta()
+	jsonResponse(w, http.StatusOK, APIResponse{Success: true, Message: "User updated", Data: user})
+}
+
+// deleteUserHandler removes a user by id.
+func deleteUserHandler(w http.ResponseWriter, r *http.Request) {
+	id, ok := idFromRoute(w, r)
+	if !ok {
+		return
+	}
+	if db == nil {
+		jsonResponse(w, http.StatusInternalServerError, APIResponse{Success: false, Message: "Database not available"})
+		return
+	}
+
+	res, err := db.Exec(rebindQuery(`DELETE FROM users WHERE id = ?`), id)
+	if err != nil {
+		jsonResponse(w, http.StatusInternalServerError, APIResponse{Success: false, Message: "Failed to delete user", Error: err.Error()})
+		return
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		jsonResponse(w, http.StatusNotFound, APIResponse{Success: false, Message: fmt.Sprintf("User %d not found", id)})
+		return
+	}
+
+	broadcastStatsDelta()
+	jsonResponse(w, http.StatusOK, APIResponse{Success: true, Message: fmt.Sprintf("User %d deleted", id)})
+}
+
+// createProjectHandler inserts a new project.
+func createProjectHandler(w http.ResponseWriter, r *http.Request) {
+	if db == nil {
+		jsonResponse(w, http.StatusInternalServerError, APIResponse{Success: false, Message: "Database not available"})
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		jsonResponse(w, http.StatusBadRequest, APIResponse{Success: false, Message: "Failed to read request body", Error: err.Error()})
+		return
+	}
+
+	var project Project
+	if err := json.Unmarshal(body, &project); err != nil {
+		jsonResponse(w, http.StatusBadRequest, APIResponse{Success: false, Message: "Invalid JSON format", Error: err.Error()})
+		return
+	}
+	if project.Name == "" {
+		jsonResponse(w, http.StatusBadRequest, APIResponse{Success: false, Message: "Name is required"})
+		return
+	}
+
+	res, err := db.Exec(
+		rebindQuery(`INSERT INTO projects (name, description, status, priority, budget, start_date, end_date) VALUES (?, ?, ?, ?, ?, ?, ?)`),
+		project.Name, project.Description, project.Status, project.Priority, project.Budget, project.StartDate, project.EndDate,
+	)
+	if err != nil {
+		jsonResponse(w, http.StatusInternalServerError, APIResponse{Success: false, Message: "Failed to create project", Error: err.Error()})
+		return
+	}
+
+	id, _ := res.LastInsertId()
+	project.ID = int(id)
+	jsonResponse(w, http.StatusCreated, APIResponse{Success: true, Message: "Project created", Data: project})
+}
+
+// getProjectHandler returns a single project, or 404 if it doesn't exist.
+func getProjectHandler(w http.ResponseWriter, r *http.Request) {
+	id, ok := idFromRoute(w, r)
+	if !ok {
+		return
+	}
+	if db == nil {
+		jsonResponse(w, http.StatusInternalServerError, APIResponse{Success: false, Message: "Database not available"})
+		return
+	}
+
+	var project Project
+	err := db.QueryRow(
+		rebindQuery(`SELECT id, name, description, status, priority, budget, start_date, end_date FROM projects WHERE id = ?`), id,
+	).Scan(&project.ID, &project.Name, &project.Description, &project.Status, &project.Priority, &project.Budget, &project.StartDate, &project.EndDate)
+	if err != nil {
+		jsonResponse(w, http.StatusNotFound, APIResponse{Success: false, Message: fmt.Sprintf("Project %d not found", id)})
+		return
+	}
+
+	jsonResponse(w, http.StatusOK, APIResponse{Success: true, Message: "Project retrieved", Data: project})
+}
+
+// updateProjectHandler updates the mutable fields of a project.
+func updateProjectHandler(w http.ResponseWriter, r *http.Request) {
+	id, ok := idFromRoute(w, r)
+	if !ok {
+		return
+	}
+	if db == nil {
+		jsonResponse(w, http.StatusInternalServerError, APIResponse{Success: false, Message: "Database not available"})
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		jsonResponse(w, http.StatusBadRequest, APIResponse{Success: false, Message: "Failed to read request body", Error: err.Error()})
+		return
+	}
+
+	var project Project
+	if err := json.Unmarshal(body, &project); err != nil {
+		jsonResponse(w, http.StatusBadRequest, APIResponse{Success: false, Message: "Invalid JSON format", Error: err.Error()})
+		return
+	}
+
+	res, err := db.Exec(
+		rebindQuery(`UPDATE projects SET name = ?, description = ?, status = ?, priority = ?, budget = ?, start_date = ?, end_date = ? WHERE id = ?`),
+		project.Name, project.Description, project.Status, project.Priority, project.Budget, project.StartDate, project.EndDate, id,
+	)
+	if err != nil {
+		jsonResponse(w, http.StatusInternalServerError, APIResponse{Success: false, Message: "Failed to update project", Error: err.Error()})
+		return
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		jsonResponse(w, http.StatusNotFound, APIResponse{Success: false, Message: fmt.Sprintf("Project %d not found", id)})
+		return
+	}
+
+	project.ID = id
+	jsonResponse(w, http.StatusOK, APIResponse{Success: true, Message: "Project updated", Data: project})
+}
+
+// deleteProjectHandler removes a project by id.
+func deleteProjectHandler(w http.ResponseWriter, r *http.Request) {
+	id, ok := idFromRoute(w, r)
+	if !ok {
+		return
+	}
+	if db == nil {
+		jsonResponse(w, http.StatusInternalServerError, APIResponse{Success: false, Message: "Database not available"})
+		return
+	}
+
+	res, err := db.Exec(rebindQuery(`DELETE FROM projects WHERE id = ?`), id)
+	if err != nil {
+		jsonResponse(w, http.StatusInternalServerError, APIResponse{Success: false, Message: "Failed to delete project", Error: err.Error()})
+		return
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		jsonResponse(w, http.StatusNotFound, APIResponse{Success: false, Message: fmt.Sprintf("Project %d not found", id)})
+		return
+	}
+
+	jsonResponse(w, http.StatusOK, APIResponse{Success: true, Message: fmt.Sprintf("Project %d deleted", id)})
+}
+
+// projectExists reports whether a project with the given id exists.
+func projectExists(projectID int) bool {
+	var id int
+	err := db.QueryRow(rebindQuery(`SELECT id FROM projects WHERE id = ?`), projectID).Scan(&id)
+	return err == nil
+}
+
+// getProjectTasksHandler lists the tasks belonging to a project.
+func getProjectTasksHandler(w http.ResponseWriter, r *http.Request) {
+	projectID, ok := idFromRoute(w, r)
+	if !ok {
+		return
+	}
+	if db == nil {
+		jsonResponse(w, http.StatusInternalServerError, APIResponse{Success: false, Message: "Database not available"})
+		return
+	}
+	if !projectExists(projectID) {
+		jsonResponse(w, http.StatusNotFound, APIResponse{Success: false, Message: fmt.Sprintf("Project %d not found", projectID)})
+		return
+	}
+
+	rows, err := db.Query(rebindQuery(`SELECT id, project_id, title, status FROM tasks WHERE project_id = ?`), projectID)
+	if err != nil {
+		jsonResponse(w, http.StatusInternalServerError, APIResponse{Success: false, Message: "Failed to query tasks", Error: err.Error()})
+		return
+	}
+	defer rows.Close()
+
+	var tasks []Task
+	for rows.Next() {
+		var task Task
+		if err := rows.Scan(&task.ID, &task.ProjectID, &task.Title, &task.Status); err == nil {
+			tasks = append(tasks, task)
+		}
+	}
+
+	jsonResponse(w, http.StatusOK, APIResponse{Success: true, Message: fmt.Sprintf("Retrieved %d tasks", len(tasks)), Data: tasks})
+}
+
+// createProjectTaskHandler adds a task to a project.
+func createProjectTaskHandler(w http.ResponseWriter, r *http.Request) {
+	projectID, ok := idFromRoute(w, r)
+	if !ok {
+		return
+	}
+	if db == nil {
+		jsonResponse(w, http.StatusInternalServerError, APIResponse{Success: false, Message: "Database not available"})
+		return
+	}
+	if !projectExists(projectID) {
+		jsonResponse(w, http.StatusNotFound, APIResponse{Success: false, Message: fmt.Sprintf("Project %d not found", projectID)})
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		jsonResponse(w, http.StatusBadRequest, APIResponse{Success: false, Message: "Failed to read request body", Error: err.Error()})
+		return
+	}
+
+	var task Task
+	if err := json.Unmarshal(body, &task); err != nil {
+		jsonResponse(w, http.StatusBadRequest, APIResponse{Success: false, Message: "Invalid JSON format", Error: err.Error()})
+		return
+	}
+	if task.Title == "" {
+		jsonResponse(w, http.StatusBadRequest, APIResponse{Success: false, Message: "Title is required"})
+		return
+	}
+	if task.Status == "" {
+		task.Status = "open"
+	}
+
+	res, err := db.Exec(rebindQuery(`INSERT INTO tasks (project_id, title, status) VALUES (?, ?, ?)`), projectID, task.Title, task.Status)
+	if err != nil {
+		jsonResponse(w, http.StatusInternalServerError, APIResponse{Success: false, Message: "Failed to create task", Error: err.Error()})
+		return
+	}
+
+	id, _ := res.LastInsertId()
+	task.ID = int(id)
+	task.ProjectID = projectID
+	broadcastStatsDelta()
+	jsonResponse(w, http.StatusCreated, APIResponse{Success: true, Message: "Task created", Data: task})
+}
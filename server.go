@@ -1,26 +1,59 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
+	"flag"
 	"fmt"
 	"io"
 	"log"
+	"net"
 	"net/http"
 	"os"
+	"os/signal"
 	"strconv"
 	"strings"
+	"syscall"
 	"time"
 
 	"database/sql"
 	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/gorilla/mux"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/af-bluec/py-101/auth"
 )
 
 // Configuration structure
 type Config struct {
-	Port         int    `json:"port"`
-	DatabasePath string `json:"database_path"`
-	Environment  string `json:"environment"`
-	Debug        bool   `json:"debug"`
+	Port           int    `json:"port" yaml:"port"`
+	DatabaseDSN    string `json:"database_dsn" yaml:"database_dsn"`
+	DatabaseDriver string `json:"database_driver" yaml:"database_driver"`
+	Environment    string `json:"environment" yaml:"environment"`
+	Debug          bool   `json:"debug" yaml:"debug"`
+	// JWTSecret is loadable from a config file like every other field
+	// and, like most of them, overridable via an environment variable
+	// (JWT_SECRET); validateConfig refuses to start outside development
+	// with the compiled-in default.
+	JWTSecret string `json:"jwt_secret" yaml:"jwt_secret"`
+
+	// ShutdownTimeoutSeconds is the graceful-shutdown drain window, in
+	// seconds. It's an int rather than a time.Duration because
+	// json.Unmarshal/yaml.Unmarshal parse a bare number as nanoseconds,
+	// which would silently turn a config file's "shutdown_timeout": 30
+	// into a 30ns drain; keeping the unit explicit matches
+	// SHUTDOWN_TIMEOUT_SECONDS, the env var equivalent.
+	ShutdownTimeoutSeconds int    `json:"shutdown_timeout" yaml:"shutdown_timeout"`
+	User                   string `json:"user" yaml:"user"`
+	Group                  string `json:"group" yaml:"group"`
+	CertFile               string `json:"cert_file" yaml:"cert_file"`
+	KeyFile                string `json:"key_file" yaml:"key_file"`
+
+	// LogLevel and CORSAllowOrigins are hot-reloadable via SIGHUP; see
+	// config.go's watchConfigReload.
+	LogLevel         string   `json:"log_level" yaml:"log_level"`
+	CORSAllowOrigins []string `json:"cors_allow_origins" yaml:"cors_allow_origins"`
 }
 
 // Response structures
@@ -32,10 +65,12 @@ type APIResponse struct {
 }
 
 type HealthStatus struct {
-	Status    string    `json:"status"`
-	Timestamp time.Time `json:"timestamp"`
-	Version   string    `json:"version"`
-	Uptime    string    `json:"uptime"`
+	Status       string    `json:"status"`
+	Timestamp    time.Time `json:"timestamp"`
+	Version      string    `json:"version"`
+	Uptime       string    `json:"uptime"`
+	DBPingMillis float64   `json:"db_ping_ms,omitempty"`
+	DBLastError  string    `json:"db_last_error,omitempty"`
 }
 
 type User struct {
@@ -62,14 +97,16 @@ type Project struct {
 // Global variables
 var (
 	db        *sql.DB
-	config    Config
 	startTime time.Time
+	authSvc   *auth.Service
 )
 
 // Initialize database connection
 func initDatabase() error {
+	cfg := getConfig()
+
 	var err error
-	db, err = sql.Open("sqlite3", config.DatabasePath)
+	db, err = sql.Open(cfg.DatabaseDriver, cfg.DatabaseDSN)
 	if err != nil {
 		return fmt.Errorf("failed to open database: %v", err)
 	}
@@ -79,57 +116,19 @@ func initDatabase() error {
 		return fmt.Errorf("failed to ping database: %v", err)
 	}
 
-	log.Printf("Database connected successfully: %s", config.DatabasePath)
-	return nil
-}
-
-// Load configuration
-func loadConfig() {
-	// Default configuration
-	config = Config{
-		Port:         8080,
-		DatabasePath: "./data/sample.db",
-		Environment:  "development",
-		Debug:        true,
-	}
-
-	// Try to load from environment variables
-	if port := os.Getenv("APP_PORT"); port != "" {
-		if p, err := strconv.Atoi(port); err == nil {
-			config.Port = p
-		}
-	}
-
-	if dbPath := os.Getenv("DATABASE_PATH"); dbPath != "" {
-		config.DatabasePath = dbPath
-	}
-
-	if env := os.Getenv("GO_ENV"); env != "" {
-		config.Environment = env
-		config.Debug = env != "production"
+	if err := runMigrations(cfg.DatabaseDriver, cfg.DatabaseDSN); err != nil {
+		return fmt.Errorf("failed to run migrations: %v", err)
 	}
 
-	log.Printf("Configuration loaded: %+v", config)
-}
-
-// Middleware for logging requests
-func loggingMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		start := time.Now()
-		
-		// Call the next handler
-		next.ServeHTTP(w, r)
-		
-		// Log the request
-		duration := time.Since(start)
-		log.Printf("%s %s %s %v", r.Method, r.RequestURI, r.RemoteAddr, duration)
-	})
+	log.Printf("Database connected successfully: driver=%s dsn=%s", cfg.DatabaseDriver, cfg.DatabaseDSN)
+	return nil
 }
 
-// Middleware for CORS
+// Middleware for CORS. Reads the allow-list via getConfig() on every
+// request so a SIGHUP config reload takes effect without a restart.
 func corsMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Access-Control-Allow-Origin", corsAllowOrigin(getConfig(), r.Header.Get("Origin")))
 		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
 		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
 
@@ -152,7 +151,7 @@ func jsonResponse(w http.ResponseWriter, status int, response APIResponse) {
 // Health check handler
 func healthHandler(w http.ResponseWriter, r *http.Request) {
 	uptime := time.Since(startTime).String()
-	
+
 	status := HealthStatus{
 		Status:    "healthy",
 		Timestamp: time.Now(),
@@ -160,11 +159,16 @@ func healthHandler(w http.ResponseWriter, r *http.Request) {
 		Uptime:    uptime,
 	}
 
-	// Check database connection
+	// Check database connection, recording ping latency and the last
+	// error (if any) in the response payload.
 	if db != nil {
+		pingStart := time.Now()
 		if err := db.Ping(); err != nil {
 			status.Status = "unhealthy"
+			status.DBLastError = err.Error()
 		}
+		status.DBPingMillis = float64(time.Since(pingStart).Microseconds()) / 1000
+		recordDBPoolMetrics()
 	}
 
 	var statusCode int
@@ -181,20 +185,25 @@ func healthHandler(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-// Users API handlers
-func usersHandler(w http.ResponseWriter, r *http.Request) {
-	switch r.Method {
-	case http.MethodGet:
-		getUsersHandler(w, r)
-	case http.MethodPost:
-		createUserHandler(w, r)
-	default:
-		jsonResponse(w, http.StatusMethodNotAllowed, APIResponse{
-			Success: false,
-			Message: "Method not allowed",
-			Error:   fmt.Sprintf("Method %s not allowed", r.Method),
-		})
+// requireAuth wraps a handler with JWT authentication and, if any roles
+// are given, an additional role check; used to gate mutation endpoints
+// without disturbing the public GET routes.
+func requireAuth(next http.HandlerFunc, roles ...string) http.HandlerFunc {
+	if authSvc == nil {
+		return func(w http.ResponseWriter, r *http.Request) {
+			jsonResponse(w, http.StatusServiceUnavailable, APIResponse{
+				Success: false,
+				Message: "Authentication not available",
+			})
+		}
 	}
+
+	handler := http.Handler(next)
+	if len(roles) > 0 {
+		handler = auth.RequireRole(roles...)(handler)
+	}
+	handler = authSvc.Middleware(handler)
+	return handler.ServeHTTP
 }
 
 // Get users handler
@@ -224,14 +233,15 @@ func getUsersHandler(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	// Query users
-	query := `
-		SELECT id, username, email, first_name, last_name, is_active, created_at 
-		FROM users 
-		WHERE is_active = 1 
-		ORDER BY created_at DESC 
-		LIMIT ? OFFSET ?
-	`
+	// Query users. Placeholders are driver-specific: sqlite3/mysql use
+	// "?", postgres uses "$n".
+	query := fmt.Sprintf(`
+		SELECT id, username, email, first_name, last_name, is_active, created_at
+		FROM users
+		WHERE is_active = 1
+		ORDER BY created_at DESC
+		LIMIT %s OFFSET %s
+	`, placeholder(getConfig().DatabaseDriver, 1), placeholder(getConfig().DatabaseDriver, 2))
 
 	rows, err := db.Query(query, limit, offset)
 	if err != nil {
@@ -286,8 +296,11 @@ func createUserHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var user User
-	if err := json.Unmarshal(body, &user); err != nil {
+	var req struct {
+		User
+		Password string `json:"password"`
+	}
+	if err := json.Unmarshal(body, &req); err != nil {
 		jsonResponse(w, http.StatusBadRequest, APIResponse{
 			Success: false,
 			Message: "Invalid JSON format",
@@ -297,31 +310,60 @@ func createUserHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Basic validation
-	if user.Username == "" || user.Email == "" {
+	if req.Username == "" || req.Email == "" || req.Password == "" {
 		jsonResponse(w, http.StatusBadRequest, APIResponse{
 			Success: false,
-			Message: "Username and email are required",
+			Message: "Username, email, and password are required",
 		})
 		return
 	}
 
-	jsonResponse(w, http.StatusCreated, APIResponse{
-		Success: true,
-		Message: "User creation endpoint (demo)",
-		Data:    user,
-	})
-}
+	if db == nil {
+		jsonResponse(w, http.StatusInternalServerError, APIResponse{
+			Success: false,
+			Message: "Database not available",
+		})
+		return
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		jsonResponse(w, http.StatusInternalServerError, APIResponse{
+			Success: false,
+			Message: "Failed to hash password",
+			Error:   err.Error(),
+		})
+		return
+	}
 
-// Projects API handler
-func projectsHandler(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		jsonResponse(w, http.StatusMethodNotAllowed, APIResponse{
+	res, err := db.Exec(
+		rebindQuery(`INSERT INTO users (username, email, first_name, last_name, password_hash, role, is_active) VALUES (?, ?, ?, ?, ?, 'user', 1)`),
+		req.Username, req.Email, req.FirstName, req.LastName, string(hash),
+	)
+	if err != nil {
+		jsonResponse(w, http.StatusConflict, APIResponse{
 			Success: false,
-			Message: "Only GET method allowed",
+			Message: "Failed to create user",
+			Error:   err.Error(),
 		})
 		return
 	}
 
+	id, _ := res.LastInsertId()
+	req.ID = int(id)
+	req.IsActive = true
+
+	broadcastStatsDelta()
+	jsonResponse(w, http.StatusCreated, APIResponse{
+		Success: true,
+		Message: "User created",
+		Data:    req.User,
+	})
+}
+
+// listProjectsHandler returns every project. Method routing (GET vs.
+// POST/PUT/DELETE) is handled by the router in setupRoutes.
+func listProjectsHandler(w http.ResponseWriter, r *http.Request) {
 	if db == nil {
 		jsonResponse(w, http.StatusInternalServerError, APIResponse{
 			Success: false,
@@ -431,7 +473,7 @@ func statsHandler(w http.ResponseWriter, r *http.Request) {
 func staticHandler(w http.ResponseWriter, r *http.Request) {
 	// Remove the /static/ prefix
 	path := strings.TrimPrefix(r.URL.Path, "/static/")
-	
+
 	// Security check: prevent directory traversal
 	if strings.Contains(path, "..") {
 		http.Error(w, "Invalid path", http.StatusBadRequest)
@@ -444,65 +486,117 @@ func staticHandler(w http.ResponseWriter, r *http.Request) {
 
 // Root handler - serve index.html
 func rootHandler(w http.ResponseWriter, r *http.Request) {
-	if r.URL.Path == "/" {
-		http.ServeFile(w, r, "index.html")
-		return
-	}
-	
-	// For any other path, return 404
-	jsonResponse(w, http.StatusNotFound, APIResponse{
-		Success: false,
-		Message: "Endpoint not found",
-		Error:   fmt.Sprintf("Path %s not found", r.URL.Path),
-	})
+	http.ServeFile(w, r, "index.html")
 }
 
-// Setup routes
-func setupRoutes() *http.ServeMux {
-	mux := http.NewServeMux()
+// Setup routes. Method routing (GET/POST/PUT/DELETE on the same path)
+// is handled by the router itself rather than per-handler switch blocks.
+func setupRoutes() *mux.Router {
+	router := mux.NewRouter()
+
+	// API routes. Each handler is wrapped in instrumentRoute with its own
+	// route pattern so /metrics cardinality stays bounded across
+	// path-parameter routes (see instrumentRoute's doc comment).
+	router.HandleFunc("/health", instrumentRoute("/health", healthHandler)).Methods(http.MethodGet)
+	router.HandleFunc("/metrics", instrumentRoute("/metrics", metricsHandler)).Methods(http.MethodGet)
+
+	router.HandleFunc("/api/users", instrumentRoute("/api/users", getUsersHandler)).Methods(http.MethodGet)
+	router.HandleFunc("/api/users", instrumentRoute("/api/users", requireAuth(createUserHandler, "admin"))).Methods(http.MethodPost)
+	router.HandleFunc("/api/users/{id}", instrumentRoute("/api/users/{id}", getUserByIDHandler)).Methods(http.MethodGet)
+	router.HandleFunc("/api/users/{id}", instrumentRoute("/api/users/{id}", requireAuth(updateUserHandler, "admin"))).Methods(http.MethodPut)
+	router.HandleFunc("/api/users/{id}", instrumentRoute("/api/users/{id}", requireAuth(deleteUserHandler, "admin"))).Methods(http.MethodDelete)
+
+	router.HandleFunc("/api/projects", instrumentRoute("/api/projects", listProjectsHandler)).Methods(http.MethodGet)
+	router.HandleFunc("/api/projects", instrumentRoute("/api/projects", requireAuth(createProjectHandler, "admin", "user"))).Methods(http.MethodPost)
+	router.HandleFunc("/api/projects/{id}", instrumentRoute("/api/projects/{id}", getProjectHandler)).Methods(http.MethodGet)
+	router.HandleFunc("/api/projects/{id}", instrumentRoute("/api/projects/{id}", requireAuth(updateProjectHandler, "admin", "user"))).Methods(http.MethodPut)
+	router.HandleFunc("/api/projects/{id}", instrumentRoute("/api/projects/{id}", requireAuth(deleteProjectHandler, "admin"))).Methods(http.MethodDelete)
+
+	router.HandleFunc("/api/projects/{id}/tasks", instrumentRoute("/api/projects/{id}/tasks", getProjectTasksHandler)).Methods(http.MethodGet)
+	router.HandleFunc("/api/projects/{id}/tasks", instrumentRoute("/api/projects/{id}/tasks", requireAuth(createProjectTaskHandler, "admin", "user"))).Methods(http.MethodPost)
+
+	router.HandleFunc("/api/stats", instrumentRoute("/api/stats", requireAuth(statsHandler, "admin", "user"))).Methods(http.MethodGet)
 
-	// API routes
-	mux.HandleFunc("/health", healthHandler)
-	mux.HandleFunc("/api/users", usersHandler)
-	mux.HandleFunc("/api/projects", projectsHandler)
-	mux.HandleFunc("/api/stats", statsHandler)
+	// Auth routes
+	if authSvc != nil {
+		router.HandleFunc("/api/register", instrumentRoute("/api/register", authSvc.RegisterHandler)).Methods(http.MethodPost)
+		router.HandleFunc("/api/login", instrumentRoute("/api/login", authSvc.LoginHandler)).Methods(http.MethodPost)
+		router.HandleFunc("/api/logout", instrumentRoute("/api/logout", authSvc.LogoutHandler)).Methods(http.MethodPost)
+	}
+
+	// GraphQL routes
+	router.HandleFunc("/query", instrumentRoute("/query", graphqlHandler))
+	router.HandleFunc("/playground", instrumentRoute("/playground", playgroundHandler)).Methods(http.MethodGet)
 
 	// Static files
-	mux.HandleFunc("/static/", staticHandler)
+	router.PathPrefix("/static/").HandlerFunc(instrumentRoute("/static/*", staticHandler))
 
 	// Root handler
-	mux.HandleFunc("/", rootHandler)
+	router.HandleFunc("/", instrumentRoute("/", rootHandler))
+	router.NotFoundHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		jsonResponse(w, http.StatusNotFound, APIResponse{
+			Success: false,
+			Message: "Endpoint not found",
+			Error:   fmt.Sprintf("Path %s not found", r.URL.Path),
+		})
+	})
 
-	return mux
+	return router
 }
 
 func main() {
 	startTime = time.Now()
-	
-	// Load configuration
-	loadConfig()
 
-	// Initialize database (optional, only if database file exists)
-	if _, err := os.Stat(config.DatabasePath); err == nil {
+	// Load configuration: defaults, overlaid by --config file, overlaid
+	// by env vars. cfg is a startup snapshot used for settings that
+	// require a restart to change (port, TLS, DB, privilege drop);
+	// handlers and middleware instead call getConfig() so log level,
+	// debug flag, and CORS allow-list can change via SIGHUP.
+	configPath := flag.String("config", os.Getenv("CONFIG_FILE"), "path to a JSON or YAML config file")
+	flag.Parse()
+
+	cfg, err := buildConfig(*configPath)
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+	currentConfig.Store(cfg)
+	watchConfigReload(*configPath)
+	initLogger(cfg.Debug)
+
+	// Initialize database. Only sqlite3 can be skipped when the file is
+	// missing; networked drivers are expected to always be reachable.
+	dbAvailable := cfg.DatabaseDriver != "sqlite3"
+	if !dbAvailable {
+		if _, err := os.Stat(cfg.DatabaseDSN); err == nil {
+			dbAvailable = true
+		} else {
+			log.Printf("Database file not found at %s, continuing without database", cfg.DatabaseDSN)
+		}
+	}
+
+	if dbAvailable {
 		if err := initDatabase(); err != nil {
 			log.Printf("Database initialization failed: %v", err)
 			log.Println("Continuing without database...")
 		} else {
 			defer db.Close()
+			authSvc = auth.NewService(db, []byte(cfg.JWTSecret), 0, cfg.DatabaseDriver)
+			authSvc.OnUserCreated = broadcastStatsDelta
 		}
-	} else {
-		log.Printf("Database file not found at %s, continuing without database", config.DatabasePath)
 	}
 
 	// Setup HTTP server
-	mux := setupRoutes()
-	
-	// Apply middleware
-	handler := corsMiddleware(loggingMiddleware(mux))
+	router := setupRoutes()
+
+	// Apply middleware. Request metrics are recorded per-route by
+	// instrumentRoute inside setupRoutes instead of here, since the route
+	// pattern isn't resolvable from outside the router (see
+	// instrumentRoute's doc comment).
+	handler := corsMiddleware(requestIDMiddleware(loggingMiddleware(router)))
 
 	// Configure server
 	server := &http.Server{
-		Addr:           fmt.Sprintf(":%d", config.Port),
+		Addr:           fmt.Sprintf(":%d", cfg.Port),
 		Handler:        handler,
 		ReadTimeout:    15 * time.Second,
 		WriteTimeout:   15 * time.Second,
@@ -510,23 +604,80 @@ func main() {
 		MaxHeaderBytes: 1 << 20, // 1MB
 	}
 
+	// Bind the listener before dropping privileges so the process can
+	// still claim privileged ports like 443.
+	listener, err := net.Listen("tcp", server.Addr)
+	if err != nil {
+		log.Fatalf("Failed to bind %s: %v", server.Addr, err)
+	}
+
+	if cfg.User != "" || cfg.Group != "" {
+		if err := dropPrivileges(cfg.User, cfg.Group); err != nil {
+			log.Fatalf("Failed to drop privileges: %v", err)
+		}
+		log.Printf("Dropped privileges to user=%q group=%q", cfg.User, cfg.Group)
+	}
+
 	// Start server
-	log.Printf("ðŸš€ Go server starting on port %d", config.Port)
-	log.Printf("Environment: %s", config.Environment)
-	log.Printf("Debug mode: %v", config.Debug)
-	
-	if config.Debug {
+	log.Printf("ðŸš€ Go server starting on port %d", cfg.Port)
+	log.Printf("Environment: %s", cfg.Environment)
+	log.Printf("Debug mode: %v", cfg.Debug)
+
+	if cfg.Debug {
 		log.Println("Available endpoints:")
-		log.Println("  GET  /              - Main page")
-		log.Println("  GET  /health        - Health check")
-		log.Println("  GET  /api/users     - Get users")
-		log.Println("  POST /api/users     - Create user (demo)")
-		log.Println("  GET  /api/projects  - Get projects")
-		log.Println("  GET  /api/stats     - Get statistics")
-		log.Println("  GET  /static/*      - Static files")
-	}
+		log.Println("  GET    /                          - Main page")
+		log.Println("  GET    /health                    - Health check")
+		log.Println("  GET    /metrics                   - Prometheus metrics")
+		log.Println("  GET    /api/users                 - List users")
+		log.Println("  POST   /api/users                 - Create user (admin)")
+		log.Println("  GET    /api/users/{id}             - Get a user")
+		log.Println("  PUT    /api/users/{id}             - Update a user (admin)")
+		log.Println("  DELETE /api/users/{id}             - Delete a user (admin)")
+		log.Println("  GET    /api/projects               - List projects")
+		log.Println("  POST   /api/projects               - Create a project (auth required)")
+		log.Println("  GET    /api/projects/{id}           - Get a project")
+		log.Println("  PUT    /api/projects/{id}           - Update a project (auth required)")
+		log.Println("  DELETE /api/projects/{id}           - Delete a project (admin)")
+		log.Println("  GET    /api/projects/{id}/tasks     - List a project's tasks")
+		log.Println("  POST   /api/projects/{id}/tasks     - Create a task (auth required)")
+		log.Println("  GET    /api/stats                  - Get statistics (auth required)")
+		log.Println("  POST   /api/register                - Register a new user")
+		log.Println("  POST   /api/login                   - Log in and receive a JWT")
+		log.Println("  POST   /api/logout                  - Revoke the current token")
+		log.Println("  POST   /query                       - GraphQL endpoint")
+		log.Println("  GET    /playground                  - GraphQL Playground UI")
+		log.Println("  GET    /static/*                    - Static files")
+	}
+
+	serverErr := make(chan error, 1)
+	go func() {
+		if cfg.CertFile != "" && cfg.KeyFile != "" {
+			server.TLSConfig = tlsServerConfig()
+			serverErr <- server.ServeTLS(listener, cfg.CertFile, cfg.KeyFile)
+		} else {
+			serverErr <- server.Serve(listener)
+		}
+	}()
+
+	// Wait for SIGINT/SIGTERM and drain in-flight requests before exiting.
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
 
-	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-		log.Fatalf("Server failed to start: %v", err)
+	select {
+	case err := <-serverErr:
+		if err != nil && err != http.ErrServerClosed {
+			log.Fatalf("Server failed to start: %v", err)
+		}
+	case sig := <-sigCh:
+		log.Printf("Received signal %s, shutting down...", sig)
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.ShutdownTimeoutSeconds)*time.Second)
+		defer cancel()
+
+		if err := server.Shutdown(ctx); err != nil {
+			log.Printf("Graceful shutdown failed: %v", err)
+		} else {
+			log.Println("Server shut down cleanly")
+		}
 	}
 }